@@ -0,0 +1,13 @@
+package mux
+
+import "context"
+
+type paramsContextKey struct{}
+
+// ParamsFromContext returns the Params Router recorded for the current
+// request, or nil if none were extracted (e.g. a path with no dynamic
+// segments, or a request not dispatched through a Router).
+func ParamsFromContext(ctx context.Context) Params {
+	params, _ := ctx.Value(paramsContextKey{}).(Params)
+	return params
+}
@@ -0,0 +1,89 @@
+package mux
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Group is a collection of routes sharing a path prefix and a middleware
+// chain, created via Router.Group.
+type Group struct {
+	router     *Router
+	prefix     string
+	middleware []func(http.Handler) http.Handler
+}
+
+// Group creates a subrouter under rt rooted at prefix, wrapping every route
+// registered through it in middleware (outermost first).
+func (rt *Router) Group(prefix string, middleware ...func(http.Handler) http.Handler) *Group {
+	return &Group{router: rt, prefix: prefix, middleware: middleware}
+}
+
+// Group creates a nested Group under g's prefix, appending middleware to
+// g's chain.
+func (g *Group) Group(prefix string, middleware ...func(http.Handler) http.Handler) *Group {
+	chain := make([]func(http.Handler) http.Handler, 0, len(g.middleware)+len(middleware))
+	chain = append(chain, g.middleware...)
+	chain = append(chain, middleware...)
+
+	return &Group{router: g.router, prefix: joinPath(g.prefix, prefix), middleware: chain}
+}
+
+// Handle registers handler to serve method requests for prefix+path,
+// wrapped by the group's middleware chain. A mux.Name("...") option names
+// the route under its full prefix+path for Router.URL.
+func (g *Group) Handle(method, path string, handler http.Handler, opts ...RouteOption) error {
+	for i := len(g.middleware) - 1; i >= 0; i-- {
+		handler = g.middleware[i](handler)
+	}
+
+	return g.router.Handle(method, joinPath(g.prefix, path), handler, opts...)
+}
+
+// GET registers handler to serve GET requests for prefix+path.
+func (g *Group) GET(path string, handler http.Handler, opts ...RouteOption) error {
+	return g.Handle(http.MethodGet, path, handler, opts...)
+}
+
+// POST registers handler to serve POST requests for prefix+path.
+func (g *Group) POST(path string, handler http.Handler, opts ...RouteOption) error {
+	return g.Handle(http.MethodPost, path, handler, opts...)
+}
+
+// PUT registers handler to serve PUT requests for prefix+path.
+func (g *Group) PUT(path string, handler http.Handler, opts ...RouteOption) error {
+	return g.Handle(http.MethodPut, path, handler, opts...)
+}
+
+// PATCH registers handler to serve PATCH requests for prefix+path.
+func (g *Group) PATCH(path string, handler http.Handler, opts ...RouteOption) error {
+	return g.Handle(http.MethodPatch, path, handler, opts...)
+}
+
+// DELETE registers handler to serve DELETE requests for prefix+path.
+func (g *Group) DELETE(path string, handler http.Handler, opts ...RouteOption) error {
+	return g.Handle(http.MethodDelete, path, handler, opts...)
+}
+
+// OPTIONS registers handler to serve OPTIONS requests for prefix+path.
+func (g *Group) OPTIONS(path string, handler http.Handler, opts ...RouteOption) error {
+	return g.Handle(http.MethodOptions, path, handler, opts...)
+}
+
+// HEAD registers handler to serve HEAD requests for prefix+path.
+func (g *Group) HEAD(path string, handler http.Handler, opts ...RouteOption) error {
+	return g.Handle(http.MethodHead, path, handler, opts...)
+}
+
+// joinPath concatenates a group prefix and a route path, ensuring exactly
+// one '/' between them.
+func joinPath(prefix, path string) string {
+	switch {
+	case prefix == "":
+		return path
+	case path == "" || path == pathDelimiter:
+		return prefix
+	default:
+		return strings.TrimSuffix(prefix, pathDelimiter) + pathDelimiter + strings.TrimPrefix(path, pathDelimiter)
+	}
+}
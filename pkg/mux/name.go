@@ -0,0 +1,162 @@
+package mux
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RouteOption configures optional metadata for a route registered through
+// Router.Handle (and its GET/POST/... sugar).
+type RouteOption func(*routeOptions)
+
+type routeOptions struct {
+	name string
+}
+
+// Name assigns a name to a route, so its URL can later be reconstructed
+// with Router.URL.
+func Name(name string) RouteOption {
+	return func(o *routeOptions) {
+		o.name = name
+	}
+}
+
+// segmentKind identifies the kind of a routeTemplate segment.
+type segmentKind int
+
+const (
+	segStatic segmentKind = iota
+	segParam
+	segGlob
+	segCatchAll
+)
+
+// templateSegment is one segment of a route's original path, as given to
+// Router.Handle, remembered so Router.URL can rebuild a concrete path.
+type templateSegment struct {
+	kind    segmentKind
+	name    string // param/glob/catch-all name; literal text for segStatic
+	pattern string // regexp (segParam) or glob (segGlob) to validate against
+}
+
+// routeTemplate is the ordered sequence of segments that made up a named
+// route's path.
+type routeTemplate struct {
+	segments []templateSegment
+}
+
+// parseTemplate splits path into the segments Router.URL needs to rebuild
+// it, using the same segment syntax as Tree.Insert.
+func parseTemplate(path string) routeTemplate {
+	var tmpl routeTemplate
+
+	for _, part := range strings.Split(strings.TrimPrefix(path, pathDelimiter), pathDelimiter) {
+		switch {
+		case part == "":
+			continue
+		case part[0] == paramDelimiter[0]:
+			tmpl.segments = append(tmpl.segments, templateSegment{
+				kind:    segParam,
+				name:    getParameter(part),
+				pattern: getPattern(part),
+			})
+		case strings.HasPrefix(part, "**"):
+			tmpl.segments = append(tmpl.segments, templateSegment{kind: segCatchAll, name: part[2:]})
+		case strings.ContainsRune(part, '*'):
+			name, pattern := "", part
+			if token := part[1:]; part[0] == '*' && isIdent(token) {
+				name, pattern = token, "*"
+			}
+			tmpl.segments = append(tmpl.segments, templateSegment{kind: segGlob, name: name, pattern: pattern})
+		default:
+			tmpl.segments = append(tmpl.segments, templateSegment{kind: segStatic, name: part})
+		}
+	}
+
+	// Tree.Insert keeps a trailing '/' as part of the route, so it is kept
+	// here as an empty final static segment.
+	if len(tmpl.segments) > 0 && strings.HasSuffix(path, pathDelimiter) {
+		tmpl.segments = append(tmpl.segments, templateSegment{kind: segStatic})
+	}
+
+	return tmpl
+}
+
+// URL reconstructs the path registered under name, substituting values for
+// its dynamic segments from the key/value pairs in params (e.g.
+// router.URL("user.show", "id", "42")). It returns an error if name is
+// unknown, a param is missing or unused, or a value doesn't satisfy its
+// segment's pattern.
+func (rt *Router) URL(name string, params ...string) (string, error) {
+	tmpl, ok := rt.names[name]
+	if !ok {
+		return "", fmt.Errorf("mux: no route named %q", name)
+	}
+
+	if len(params)%2 != 0 {
+		return "", fmt.Errorf("mux: URL(%q, ...): odd number of param arguments", name)
+	}
+
+	values := make(map[string]string, len(params)/2)
+	for i := 0; i < len(params); i += 2 {
+		values[params[i]] = params[i+1]
+	}
+
+	used := make(map[string]bool, len(values))
+
+	var b strings.Builder
+	for _, seg := range tmpl.segments {
+		b.WriteString(pathDelimiter)
+
+		if seg.kind == segStatic {
+			b.WriteString(seg.name)
+			continue
+		}
+
+		if seg.name == "" {
+			return "", fmt.Errorf("mux: URL(%q, ...): route has an unnamed dynamic segment and cannot be reconstructed", name)
+		}
+
+		value, ok := values[seg.name]
+		if !ok {
+			return "", fmt.Errorf("mux: URL(%q, ...): missing param %q", name, seg.name)
+		}
+
+		if seg.kind == segParam || seg.kind == segGlob {
+			// A single segment can never contain '/', regardless of what
+			// the pattern itself allows (the default param pattern,
+			// ptnWildcard, is unanchored and would otherwise accept one).
+			if strings.ContainsRune(value, '/') {
+				return "", fmt.Errorf("mux: URL(%q, ...): param %q value %q cannot contain %q", name, seg.name, value, pathDelimiter)
+			}
+
+			cache := regC.Get
+			if seg.kind == segGlob {
+				cache = globC.Get
+			}
+
+			re, err := cache(seg.pattern)
+			if err != nil {
+				return "", err
+			}
+			if !re.MatchString(value) {
+				return "", fmt.Errorf("mux: URL(%q, ...): param %q value %q does not match pattern %q", name, seg.name, value, seg.pattern)
+			}
+		}
+
+		used[seg.name] = true
+		b.WriteString(value)
+	}
+
+	for k := range values {
+		if !used[k] {
+			return "", fmt.Errorf("mux: URL(%q, ...): unknown param %q", name, k)
+		}
+	}
+
+	if b.Len() == 0 {
+		return pathDelimiter, nil
+	}
+
+	return b.String(), nil
+}
@@ -0,0 +1,168 @@
+package mux
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// defaultNamespace is the single Tree namespace Router stores every route
+// under; method dispatch is handled by Tree's per-node handler keys, not
+// by namespace.
+const defaultNamespace = ""
+
+// Router adapts Tree to serve plain net/http traffic. It dispatches on
+// method as well as path, answers OPTIONS automatically, and replies with
+// 405 Method Not Allowed and an Allow header when a path matches but the
+// method doesn't.
+type Router struct {
+	tree  *Tree
+	names map[string]routeTemplate
+
+	// NotFoundHandler answers requests whose path matches no registered
+	// route. Defaults to http.NotFoundHandler().
+	NotFoundHandler http.Handler
+	// MethodNotAllowedHandler answers requests whose path matches but
+	// whose method doesn't; the Allow header has already been set.
+	MethodNotAllowedHandler http.Handler
+}
+
+// NewRouter creates a Router with default not-found and method-not-allowed
+// handlers.
+func NewRouter() *Router {
+	return &Router{
+		tree:                    NewTree(),
+		NotFoundHandler:         http.NotFoundHandler(),
+		MethodNotAllowedHandler: http.HandlerFunc(methodNotAllowed),
+	}
+}
+
+func methodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	http.Error(w, "405 method not allowed", http.StatusMethodNotAllowed)
+}
+
+// Handle registers handler to serve method requests for path. It returns
+// an error if method is already registered for path. If opts names the
+// route (mux.Name("...")), its URL can later be rebuilt with Router.URL.
+func (rt *Router) Handle(method, path string, handler http.Handler, opts ...RouteOption) error {
+	if rt.tree == nil {
+		rt.tree = NewTree()
+	}
+
+	if err := rt.tree.Insert(defaultNamespace, path, method, handler); err != nil {
+		return err
+	}
+
+	var ro routeOptions
+	for _, opt := range opts {
+		opt(&ro)
+	}
+
+	if ro.name != "" {
+		if rt.names == nil {
+			rt.names = make(map[string]routeTemplate)
+		}
+		rt.names[ro.name] = parseTemplate(path)
+	}
+
+	return nil
+}
+
+// GET registers handler to serve GET requests for path.
+func (rt *Router) GET(path string, handler http.Handler, opts ...RouteOption) error {
+	return rt.Handle(http.MethodGet, path, handler, opts...)
+}
+
+// POST registers handler to serve POST requests for path.
+func (rt *Router) POST(path string, handler http.Handler, opts ...RouteOption) error {
+	return rt.Handle(http.MethodPost, path, handler, opts...)
+}
+
+// PUT registers handler to serve PUT requests for path.
+func (rt *Router) PUT(path string, handler http.Handler, opts ...RouteOption) error {
+	return rt.Handle(http.MethodPut, path, handler, opts...)
+}
+
+// PATCH registers handler to serve PATCH requests for path.
+func (rt *Router) PATCH(path string, handler http.Handler, opts ...RouteOption) error {
+	return rt.Handle(http.MethodPatch, path, handler, opts...)
+}
+
+// DELETE registers handler to serve DELETE requests for path.
+func (rt *Router) DELETE(path string, handler http.Handler, opts ...RouteOption) error {
+	return rt.Handle(http.MethodDelete, path, handler, opts...)
+}
+
+// OPTIONS registers handler to serve OPTIONS requests for path, overriding
+// the automatic OPTIONS response Router otherwise provides.
+func (rt *Router) OPTIONS(path string, handler http.Handler, opts ...RouteOption) error {
+	return rt.Handle(http.MethodOptions, path, handler, opts...)
+}
+
+// HEAD registers handler to serve HEAD requests for path.
+func (rt *Router) HEAD(path string, handler http.Handler, opts ...RouteOption) error {
+	return rt.Handle(http.MethodHead, path, handler, opts...)
+}
+
+// ServeHTTP implements http.Handler. Router is usable as a zero-value
+// struct literal, not just via NewRouter: an empty tree and unset handler
+// fields fall back to the same defaults NewRouter would have set.
+func (rt *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var result *Result
+	var err error
+	if rt.tree != nil {
+		result, err = rt.tree.Search(defaultNamespace, req.URL.Path)
+	} else {
+		err = errors.New("tree is empty")
+	}
+	if err != nil {
+		rt.notFoundHandler().ServeHTTP(w, req)
+		return
+	}
+
+	if h, ok := result.Handlers[req.Method]; ok {
+		handler := h.(http.Handler)
+		ctx := context.WithValue(req.Context(), paramsContextKey{}, result.Params)
+		handler.ServeHTTP(w, req.WithContext(ctx))
+		return
+	}
+
+	if len(result.Handlers) == 0 {
+		rt.notFoundHandler().ServeHTTP(w, req)
+		return
+	}
+
+	methods := make([]string, 0, len(result.Handlers))
+	for method := range result.Handlers {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+
+	if req.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	rt.methodNotAllowedHandler().ServeHTTP(w, req)
+}
+
+// notFoundHandler returns rt.NotFoundHandler, or the same default
+// NewRouter installs if rt was built as a zero-value Router{}.
+func (rt *Router) notFoundHandler() http.Handler {
+	if rt.NotFoundHandler != nil {
+		return rt.NotFoundHandler
+	}
+	return http.NotFoundHandler()
+}
+
+// methodNotAllowedHandler returns rt.MethodNotAllowedHandler, or the same
+// default NewRouter installs if rt was built as a zero-value Router{}.
+func (rt *Router) methodNotAllowedHandler() http.Handler {
+	if rt.MethodNotAllowedHandler != nil {
+		return rt.MethodNotAllowedHandler
+	}
+	return http.HandlerFunc(methodNotAllowed)
+}
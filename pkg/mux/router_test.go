@@ -0,0 +1,103 @@
+package mux
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestZeroValueRouterServesNotFound(t *testing.T) {
+	var rt Router
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/anything", nil)
+
+	rt.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestZeroValueRouterCanRegisterAndServeRoutes(t *testing.T) {
+	var rt Router
+
+	if err := rt.GET("/widgets", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})); err != nil {
+		t.Fatalf("GET /widgets: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/widgets", nil)
+
+	rt.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestGroupMiddlewareInvocationOrder(t *testing.T) {
+	rt := NewRouter()
+
+	var order []string
+	mark := func(name string) func(http.Handler) http.Handler {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	g := rt.Group("/api", mark("outer"), mark("inner"))
+	if err := g.GET("/ping", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		order = append(order, "handler")
+	})); err != nil {
+		t.Fatalf("GET /api/ping: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/ping", nil)
+	rt.ServeHTTP(rec, req)
+
+	want := []string{"outer", "inner", "handler"}
+	if len(order) != len(want) {
+		t.Fatalf("got order %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("got order %v, want %v", order, want)
+		}
+	}
+}
+
+func TestRouterURLErrors(t *testing.T) {
+	rt := NewRouter()
+
+	noop := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	if err := rt.GET("/users/:id[^\\d+$]", noop, Name("user.show")); err != nil {
+		t.Fatalf("GET /users/:id[^\\d+$]: %v", err)
+	}
+
+	if got, err := rt.URL("user.show", "id", "42"); err != nil || got != "/users/42" {
+		t.Fatalf("URL(user.show, id, 42) = %q, %v, want /users/42, nil", got, err)
+	}
+
+	if _, err := rt.URL("user.show"); err == nil {
+		t.Fatal("URL(user.show) with no params: expected a missing-param error, got nil")
+	}
+
+	if _, err := rt.URL("user.show", "id", "42", "extra", "1"); err == nil {
+		t.Fatal("URL(user.show, ..., extra, 1): expected an unknown-param error, got nil")
+	}
+
+	if _, err := rt.URL("user.show", "id", "nope"); err == nil {
+		t.Fatal("URL(user.show, id, nope): expected a pattern-mismatch error, got nil")
+	}
+
+	if _, err := rt.URL("no.such.route"); err == nil {
+		t.Fatal("URL(no.such.route): expected an unknown-route error, got nil")
+	}
+}
@@ -28,20 +28,36 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
 )
 
-// Tree is a trie tree.
+// Tree is a radix tree that maps paths to handlers, keyed by namespace.
 type Tree struct {
 	namespace map[string]*Node
 }
 
-// Node is a node of tree.
+// Node is a node of a radix tree. The prefix is the common edge label
+// leading into the node from its parent; statics holds the node's literal
+// children, while param, glob and catchAll each hold at most one dynamic
+// child.
 type Node struct {
-	label    string
-	handlers []interface{}
-	children map[string]*Node
+	prefix   string
+	handlers map[string]interface{}
+
+	statics []*Node // sorted by statics[i].prefix[0]
+
+	param     *Node
+	paramName string
+	paramPtn  string
+
+	glob        *Node
+	globName    string
+	globPattern string
+
+	catchAll     *Node
+	catchAllName string
 }
 
 // Param is parameter.
@@ -55,7 +71,7 @@ type Params []*Param
 
 // Result is a search result.
 type Result struct {
-	Handlers []interface{}
+	Handlers map[string]interface{}
 	Params   Params
 }
 
@@ -67,62 +83,244 @@ const (
 	ptnWildcard       = "(.+)"
 )
 
-// NewTree creates a new trie tree.
+// NewTree creates a new radix tree.
 func NewTree(namespaces ...string) *Tree {
 	namespace := make(map[string]*Node, len(namespaces))
 	for _, name := range namespaces {
-		namespace[name] = &Node{
-			label:    "",
-			handlers: nil,
-			children: make(map[string]*Node),
-		}
+		namespace[name] = &Node{}
 	}
 	return &Tree{namespace}
 }
 
-// Insert inserts a route definition to tree.
-func (t *Tree) Insert(namespace string, path string, handler interface{}) error {
-	curNode, present := t.namespace[namespace]
+// Insert inserts a route definition to tree, keyed within its node by key
+// (Router uses the HTTP method as key, so GET and POST on the same path
+// live side by side). Insert rejects a key already registered at path.
+func (t *Tree) Insert(namespace string, path string, key string, handler interface{}) error {
+	root, ok := t.namespace[namespace]
+	if !ok {
+		root = &Node{}
+		t.namespace[namespace] = root
+	}
+
+	return root.insert(strings.TrimPrefix(path, pathDelimiter), key, handler)
+}
 
-	if !present {
-		curNode = &Node{
-			label:    "",
-			handlers: nil,
-			children: make(map[string]*Node),
+// insert inserts handler under key for the remaining path under n,
+// splitting or creating static edges as needed.
+func (n *Node) insert(path string, key string, handler interface{}) error {
+	if path == "" {
+		if _, exists := n.handlers[key]; exists {
+			return fmt.Errorf("a handler is already registered for %q at this path", key)
 		}
-		t.namespace[namespace] = curNode
+		if n.handlers == nil {
+			n.handlers = make(map[string]interface{})
+		}
+		n.handlers[key] = handler
+		return nil
+	}
+
+	if segment, _ := cutSegment(path); segment != "" && (segment[0] == paramDelimiter[0] || strings.ContainsRune(segment, '*')) {
+		return n.insertDynamic(path, key, handler)
 	}
 
-	if path == pathDelimiter {
-		if len(curNode.label) != 0 && curNode.handlers == nil {
-			return errors.New("Root node already exists")
+	chunk := staticChunk(path)
+
+	for _, child := range n.statics {
+		if child.prefix[0] != chunk[0] {
+			continue
 		}
 
-		curNode.label = path
-		if curNode.handlers == nil {
-			curNode.handlers = []interface{}{handler}
-		} else {
-			curNode.handlers = append(curNode.handlers, handler)
+		cp := commonPrefixLen(child.prefix, chunk)
+		if cp < len(child.prefix) {
+			child.split(cp)
 		}
 
-		return nil
+		return child.insert(path[cp:], key, handler)
 	}
 
-	for _, l := range deleteEmpty(strings.Split(path, pathDelimiter)) {
-		if nextNode, ok := curNode.children[l]; ok {
-			curNode = nextNode
-		} else {
-			curNode.children[l] = &Node{
-				label:    l,
-				handlers: []interface{}{handler},
-				children: make(map[string]*Node),
-			}
+	child := &Node{prefix: chunk}
+	n.statics = append(n.statics, child)
+	n.sortStatics()
+
+	return child.insert(path[len(chunk):], key, handler)
+}
+
+// insertDynamic inserts a `:name[pattern]`, `*glob` or `**catchall` segment
+// starting at path.
+func (n *Node) insertDynamic(path string, key string, handler interface{}) error {
+	if path[0] == paramDelimiter[0] {
+		return n.insertParam(path, key, handler)
+	}
+
+	if strings.HasPrefix(path, "**") {
+		return n.insertCatchAll(path, key, handler)
+	}
+
+	return n.insertGlob(path, key, handler)
+}
+
+func (n *Node) insertParam(path string, key string, handler interface{}) error {
+	segment, rest := cutSegment(path)
+
+	name := getParameter(segment)
+	ptn := getPattern(segment)
+
+	if n.param == nil {
+		n.param = &Node{}
+		n.paramName, n.paramPtn = name, ptn
+	} else if n.paramName != name || n.paramPtn != ptn {
+		return fmt.Errorf("%q conflicts with existing param edge %q", segment, ":"+n.paramName+"["+n.paramPtn+"]")
+	}
+
+	return n.param.insert(rest, key, handler)
+}
+
+// insertGlob inserts a single-segment glob: a bare `*` or named `*name`
+// matches any one segment, while a pattern such as `*.ext` or `prefix-*`
+// matches within the segment.
+func (n *Node) insertGlob(path string, key string, handler interface{}) error {
+	segment, rest := cutSegment(path)
+
+	name, pattern := "", segment
+	if segment[0] == '*' && isIdent(segment[1:]) {
+		name, pattern = segment[1:], "*"
+	}
+
+	if _, err := globC.Get(pattern); err != nil {
+		return err
+	}
+
+	if n.glob == nil {
+		n.glob = &Node{}
+		n.globName, n.globPattern = name, pattern
+	} else if n.globName != name || n.globPattern != pattern {
+		return fmt.Errorf("%q conflicts with existing glob edge %q", segment, globLabel(n.globName, n.globPattern))
+	}
+
+	return n.glob.insert(rest, key, handler)
+}
+
+// insertCatchAll inserts a `**` or named `**rest` segment, which greedily
+// matches zero or more trailing segments and so must end the path.
+func (n *Node) insertCatchAll(path string, key string, handler interface{}) error {
+	if strings.ContainsRune(path, '/') {
+		return fmt.Errorf("catch-all segment %q must be the last segment in the path", path)
+	}
+
+	name := path[2:]
+	if n.catchAll == nil {
+		n.catchAll = &Node{}
+		n.catchAllName = name
+	} else if n.catchAllName != name {
+		return fmt.Errorf("%q conflicts with existing catch-all edge %q", path, "**"+n.catchAllName)
+	}
+
+	return n.catchAll.insert("", key, handler)
+}
+
+// cutSegment splits path at the first '/', returning the leading segment
+// and the remainder (including the leading '/'), or (path, "") if path has
+// no further segment boundary.
+func cutSegment(path string) (segment, rest string) {
+	if i := strings.IndexByte(path, '/'); i != -1 {
+		return path[:i], path[i:]
+	}
+	return path, ""
+}
+
+// isIdent reports whether s looks like a param name rather than a literal
+// glob pattern, so that "*name" can be told apart from "*.ext".
+func isIdent(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+		case c >= '0' && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// globLabel reconstructs the segment syntax for a glob edge, for use in
+// conflict error messages: a named edge is "*name", an unnamed one is its
+// pattern (e.g. "*.ext" or "prefix-*").
+func globLabel(name, pattern string) string {
+	if name != "" {
+		return "*" + name
+	}
+	return pattern
+}
+
+// split breaks n's prefix at byte offset at, moving everything beyond that
+// point (handlers, children) into a new static child.
+func (n *Node) split(at int) {
+	child := &Node{
+		prefix:       n.prefix[at:],
+		handlers:     n.handlers,
+		statics:      n.statics,
+		param:        n.param,
+		paramName:    n.paramName,
+		paramPtn:     n.paramPtn,
+		glob:         n.glob,
+		globName:     n.globName,
+		globPattern:  n.globPattern,
+		catchAll:     n.catchAll,
+		catchAllName: n.catchAllName,
+	}
+
+	n.prefix = n.prefix[:at]
+	n.handlers = nil
+	n.statics = []*Node{child}
+	n.param, n.paramName, n.paramPtn = nil, "", ""
+	n.glob, n.globName, n.globPattern = nil, "", ""
+	n.catchAll, n.catchAllName = nil, ""
+}
+
+func (n *Node) sortStatics() {
+	sort.Slice(n.statics, func(i, j int) bool {
+		return n.statics[i].prefix[0] < n.statics[j].prefix[0]
+	})
+}
+
+func (n *Node) isEmpty() bool {
+	return n.handlers == nil && len(n.statics) == 0 && n.param == nil && n.glob == nil && n.catchAll == nil
+}
+
+// staticChunk returns the leading static portion of path, i.e. everything
+// up to (and including) the '/' that introduces the next dynamic segment
+// (one starting with `:` or containing a `*` anywhere, e.g. `prefix-*`),
+// or the whole path if it contains no further dynamic segment.
+func staticChunk(path string) string {
+	idx := 0
+	for {
+		slash := strings.IndexByte(path[idx:], '/')
+		if slash == -1 {
+			return path
+		}
 
-			curNode = curNode.children[l]
+		segStart := idx + slash + 1
+		segment, _ := cutSegment(path[segStart:])
+		if segment != "" && (segment[0] == paramDelimiter[0] || strings.ContainsRune(segment, '*')) {
+			return path[:segStart]
 		}
+
+		idx = segStart
+	}
+}
+
+func commonPrefixLen(a, b string) int {
+	max := len(a)
+	if len(b) < max {
+		max = len(b)
 	}
 
-	return nil
+	i := 0
+	for i < max && a[i] == b[i] {
+		i++
+	}
+	return i
 }
 
 type regCache struct {
@@ -149,75 +347,131 @@ func (rc *regCache) Get(ptn string) (*regexp.Regexp, error) {
 
 var regC = &regCache{}
 
+type globCache struct {
+	s sync.Map
+}
+
+// Get gets a compiled glob pattern from cache or compiles and caches it.
+func (gc *globCache) Get(pattern string) (*regexp.Regexp, error) {
+	if v, ok := gc.s.Load(pattern); ok {
+		reg, ok := v.(*regexp.Regexp)
+		if !ok {
+			return nil, fmt.Errorf("the value of %q is wrong", pattern)
+		}
+		return reg, nil
+	}
+	reg, err := compileGlob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	gc.s.Store(pattern, reg)
+	return reg, nil
+}
+
+var globC = &globCache{}
+
+// compileGlob translates a single-segment glob pattern, where '*' matches
+// any run of characters, into an anchored regexp.
+func compileGlob(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteByte('^')
+	for _, r := range pattern {
+		if r == '*' {
+			b.WriteString(".*")
+			continue
+		}
+		b.WriteString(regexp.QuoteMeta(string(r)))
+	}
+	b.WriteByte('$')
+
+	return regexp.Compile(b.String())
+}
+
 // Search searches a path from a tree.
 func (t *Tree) Search(namespace string, path string) (*Result, error) {
+	root, ok := t.namespace[namespace]
+	if !ok || root.isEmpty() {
+		return nil, errors.New("tree is empty")
+	}
+
 	var params Params
+	node, err := root.search(strings.TrimPrefix(path, pathDelimiter), &params)
+	if err != nil {
+		return &Result{}, err
+	}
 
-	n := t.namespace[namespace]
+	return &Result{Handlers: node.handlers, Params: params}, nil
+}
 
-	if len(n.label) == 0 && len(n.children) == 0 {
-		return nil, errors.New("tree is empty")
+// search walks path from n, trying static edges, then the param edge, then
+// the glob edge, then the catch-all edge, backtracking on failure so the
+// first full match found respects that priority.
+func (n *Node) search(path string, params *Params) (*Node, error) {
+	if path == "" {
+		if n.handlers != nil {
+			return n, nil
+		}
+		// A "**" catch-all also matches zero segments.
+		if n.catchAll != nil && n.catchAll.handlers != nil {
+			if n.catchAllName != "" {
+				*params = append(*params, &Param{key: n.catchAllName, value: ""})
+			}
+			return n.catchAll, nil
+		}
+		return nil, errors.New("handler is not registered")
+	}
+
+	for _, child := range n.statics {
+		if child.prefix[0] != path[0] || !strings.HasPrefix(path, child.prefix) {
+			continue
+		}
+		if found, err := child.search(path[len(child.prefix):], params); err == nil {
+			return found, nil
+		}
 	}
 
-	label := deleteEmpty(strings.Split(path, pathDelimiter))
-	curNode := n
-
-	for _, l := range label {
-		if nextNode, ok := curNode.children[l]; ok {
-			curNode = nextNode
-		} else {
-			// pattern matching priority depends on an order of routing definition
-			// ex.
-			// 1 /foo/:id
-			// 2 /foo/:id[^\d+$]
-			// 3 /foo/:id[^\w+$]
-			// priority is 1, 2, 3
-			if len(curNode.children) == 0 {
-				return &Result{}, errors.New("handler is not registered")
+	segment, rest := cutSegment(path)
+
+	if n.param != nil {
+		reg, err := regC.Get(n.paramPtn)
+		if err != nil {
+			return nil, err
+		}
+		if reg.MatchString(segment) {
+			*params = append(*params, &Param{key: n.paramName, value: segment})
+			if found, err := n.param.search(rest, params); err == nil {
+				return found, nil
 			}
+			*params = (*params)[:len(*params)-1]
+		}
+	}
 
-			count := 0
-			for c := range curNode.children {
-				if string([]rune(c)[0]) == paramDelimiter {
-					ptn := getPattern(c)
-
-					reg, err := regC.Get(ptn)
-					if err != nil {
-						return nil, err
-					}
-					if reg.Match([]byte(l)) {
-						param := getParameter(c)
-						params = append(params, &Param{
-							key:   param,
-							value: l,
-						})
-
-						curNode = curNode.children[c]
-						count++
-						break
-					} else {
-						return &Result{}, errors.New("param does not match")
-					}
-				}
-
-				count++
-
-				// If no match is found until the last loop.
-				if count == len(curNode.children) {
-					return &Result{}, errors.New("handler is not registered")
-				}
+	if n.glob != nil {
+		re, err := globC.Get(n.globPattern)
+		if err != nil {
+			return nil, err
+		}
+		if re.MatchString(segment) {
+			if n.globName != "" {
+				*params = append(*params, &Param{key: n.globName, value: segment})
+			}
+			if found, err := n.glob.search(rest, params); err == nil {
+				return found, nil
+			}
+			if n.globName != "" {
+				*params = (*params)[:len(*params)-1]
 			}
 		}
 	}
 
-	if curNode.handlers == nil {
-		return &Result{}, errors.New("handler is not registered")
+	if n.catchAll != nil {
+		if n.catchAllName != "" {
+			*params = append(*params, &Param{key: n.catchAllName, value: path})
+		}
+		return n.catchAll, nil
 	}
 
-	return &Result{
-		Handlers: curNode.handlers,
-		Params:   params,
-	}, nil
+	return nil, errors.New("handler is not registered")
 }
 
 // getPattern gets a pattern from a label.
@@ -263,13 +517,3 @@ func getParameter(label string) string {
 
 	return label[leftI+1 : rightI]
 }
-
-func deleteEmpty(s []string) []string {
-	var r []string
-	for _, str := range s {
-		if str != "" {
-			r = append(r, str)
-		}
-	}
-	return r
-}
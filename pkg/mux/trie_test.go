@@ -0,0 +1,116 @@
+package mux
+
+import "testing"
+
+func TestInsertSplitPreservesIntermediateHandler(t *testing.T) {
+	tree := NewTree()
+
+	if err := tree.Insert("", "/abc", "h", "abc-handler"); err != nil {
+		t.Fatalf("insert /abc: %v", err)
+	}
+	if err := tree.Insert("", "/ab", "h", "ab-handler"); err != nil {
+		t.Fatalf("insert /ab: %v", err)
+	}
+
+	result, err := tree.Search("", "/ab")
+	if err != nil {
+		t.Fatalf("search /ab: %v", err)
+	}
+	if got := result.Handlers["h"]; got != "ab-handler" {
+		t.Fatalf("search /ab: got handler %v, want ab-handler", got)
+	}
+
+	result, err = tree.Search("", "/abc")
+	if err != nil {
+		t.Fatalf("search /abc: %v", err)
+	}
+	if got := result.Handlers["h"]; got != "abc-handler" {
+		t.Fatalf("search /abc: got handler %v, want abc-handler", got)
+	}
+}
+
+func TestSearchPriorityAndBacktracking(t *testing.T) {
+	tree := NewTree()
+
+	if err := tree.Insert("", "/x/42/edit", "h", "literal"); err != nil {
+		t.Fatalf("insert /x/42/edit: %v", err)
+	}
+	if err := tree.Insert("", "/x/:id[^\\d+$]/edit", "h", "param"); err != nil {
+		t.Fatalf("insert /x/:id[^\\d+$]/edit: %v", err)
+	}
+	if err := tree.Insert("", "/x/*/view", "h", "glob"); err != nil {
+		t.Fatalf("insert /x/*/view: %v", err)
+	}
+
+	cases := []struct {
+		path string
+		want string
+	}{
+		{"/x/42/edit", "literal"}, // an exact literal edge beats the param edge
+		{"/x/7/edit", "param"},    // no literal edge for "7", falls through to the param edge
+		{"/x/val/view", "glob"},   // "val" fails the param's digit pattern, backtracks to the glob edge
+	}
+
+	for _, tt := range cases {
+		result, err := tree.Search("", tt.path)
+		if err != nil {
+			t.Fatalf("search %q: %v", tt.path, err)
+		}
+		if got := result.Handlers["h"]; got != tt.want {
+			t.Fatalf("search %q: got handler %v, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestSearchCatchAllMatchesZeroSegments(t *testing.T) {
+	tree := NewTree()
+
+	if err := tree.Insert("", "/assets/**rest", "h", "assets"); err != nil {
+		t.Fatalf("insert /assets/**rest: %v", err)
+	}
+
+	result, err := tree.Search("", "/assets/")
+	if err != nil {
+		t.Fatalf("search /assets/: %v", err)
+	}
+	if got := result.Handlers["h"]; got != "assets" {
+		t.Fatalf("search /assets/: got handler %v, want assets", got)
+	}
+	if len(result.Params) != 1 || result.Params[0].key != "rest" || result.Params[0].value != "" {
+		t.Fatalf("search /assets/: got params %+v, want a single rest=\"\"", result.Params)
+	}
+}
+
+func TestSearchReturnsFullMethodSet(t *testing.T) {
+	tree := NewTree()
+
+	if err := tree.Insert("", "/widgets", "GET", "list"); err != nil {
+		t.Fatalf("insert GET /widgets: %v", err)
+	}
+	if err := tree.Insert("", "/widgets", "POST", "create"); err != nil {
+		t.Fatalf("insert POST /widgets: %v", err)
+	}
+
+	result, err := tree.Search("", "/widgets")
+	if err != nil {
+		t.Fatalf("search /widgets: %v", err)
+	}
+	if len(result.Handlers) != 2 {
+		t.Fatalf("got %d handlers, want 2: %+v", len(result.Handlers), result.Handlers)
+	}
+	if result.Handlers["GET"] != "list" || result.Handlers["POST"] != "create" {
+		t.Fatalf("unexpected handlers: %+v", result.Handlers)
+	}
+}
+
+func TestInsertGlobRejectsNameConflict(t *testing.T) {
+	tree := NewTree()
+
+	if err := tree.Insert("", "/x/*a/p", "h", "p-handler"); err != nil {
+		t.Fatalf("insert /x/*a/p: %v", err)
+	}
+
+	if err := tree.Insert("", "/x/*b/q", "h", "q-handler"); err == nil {
+		t.Fatal("insert /x/*b/q: expected a conflict error for the renamed glob edge, got nil")
+	}
+}